@@ -0,0 +1,56 @@
+package contenttype
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+)
+
+var (
+	// Charset in the Accept-Charset header is syntactically invalid.
+	ErrInvalidCharset = errors.New("Invalid charset")
+	// Accept-Charset header contains only charsets that are not in the acceptable charset list.
+	ErrNoAcceptableCharsetFound = errors.New("No acceptable charset found")
+	// Acceptable charset list is empty.
+	ErrNoAvailableCharsetGiven = errors.New("No available charset given")
+)
+
+// Choses a charset from available charsets according to the Accept-Charset header.
+// Returns the most suitable charset or an error if no charset can be selected.
+// "*" in the header applies to any charset not otherwise mentioned (RFC 9110, 12.5.5).
+func GetAcceptableCharset(request *http.Request, availableCharsets []string) (string, error) {
+	if len(availableCharsets) == 0 {
+		return "", ErrNoAvailableCharsetGiven
+	}
+
+	acceptCharsetHeaders := request.Header.Values("Accept-Charset")
+	if len(acceptCharsetHeaders) == 0 {
+		return availableCharsets[0], nil
+	}
+
+	items, err := parseWeightedList(acceptCharsetHeaders[0], ErrInvalidCharset)
+	if err != nil {
+		return "", err
+	}
+
+	matches := make([]weightedMatch, len(availableCharsets))
+
+	for order, item := range items {
+		for i, charset := range availableCharsets {
+			// RFC 9110, 12.5.5: charset names are case-insensitive
+			exact := strings.EqualFold(item.value, charset)
+			if !exact && item.value != "*" {
+				continue
+			}
+
+			matches[i].record(item.weight, order, exact)
+		}
+	}
+
+	resultIndex := bestMatch(matches)
+	if resultIndex == -1 {
+		return "", ErrNoAcceptableCharsetFound
+	}
+
+	return availableCharsets[resultIndex], nil
+}
@@ -0,0 +1,71 @@
+package contenttype
+
+import (
+	"log"
+	"net/http"
+	"testing"
+)
+
+func TestGetAcceptableCharset(t *testing.T) {
+	testCases := []struct {
+		header            string
+		availableCharsets []string
+		result            string
+	}{
+		{"", []string{"utf-8"}, "utf-8"},
+		{"utf-8", []string{"utf-8"}, "utf-8"},
+		{"iso-8859-1;q=0.5,utf-8", []string{"iso-8859-1", "utf-8"}, "utf-8"},
+		{"*", []string{"utf-8"}, "utf-8"},
+		{"utf-8", []string{"UTF-8"}, "UTF-8"},
+	}
+
+	for _, testCase := range testCases {
+		request, requestError := http.NewRequest(http.MethodGet, "http://test.test", nil)
+		if requestError != nil {
+			log.Fatal(requestError)
+		}
+
+		if len(testCase.header) > 0 {
+			request.Header.Set("Accept-Charset", testCase.header)
+		}
+
+		result, charsetError := GetAcceptableCharset(request, testCase.availableCharsets)
+		if charsetError != nil {
+			t.Errorf("Unexpected error \"%s\" for %s", charsetError.Error(), testCase.header)
+		} else if result != testCase.result {
+			t.Errorf("Invalid charset, got %s, expected %s for %s", result, testCase.result, testCase.header)
+		}
+	}
+}
+
+func TestGetAcceptableCharsetErrors(t *testing.T) {
+	testCases := []struct {
+		header            string
+		availableCharsets []string
+		err               error
+	}{
+		{"", []string{}, ErrNoAvailableCharsetGiven},
+		{"iso-8859-1", []string{"utf-8"}, ErrNoAcceptableCharsetFound},
+		{"utf-8;q=0", []string{"utf-8"}, ErrNoAcceptableCharsetFound},
+		{"utf-8,", []string{"utf-8"}, ErrInvalidCharset},
+		{"utf-8;q=a", []string{"utf-8"}, ErrInvalidWeight},
+	}
+
+	for _, testCase := range testCases {
+		request, requestError := http.NewRequest(http.MethodGet, "http://test.test", nil)
+		if requestError != nil {
+			log.Fatal(requestError)
+		}
+
+		if len(testCase.header) > 0 {
+			request.Header.Set("Accept-Charset", testCase.header)
+		}
+
+		_, charsetError := GetAcceptableCharset(request, testCase.availableCharsets)
+		if charsetError == nil {
+			t.Errorf("Expected an error for %s", testCase.header)
+		} else if testCase.err != charsetError {
+			t.Errorf("Unexpected error \"%s\", expected \"%s\" for %s", charsetError.Error(), testCase.err.Error(), testCase.header)
+		}
+	}
+}
@@ -0,0 +1,487 @@
+// Package implement HTTP Content-Type and Accept header parsers.
+package contenttype
+
+import (
+	"errors"
+	"net/http"
+	"sort"
+	"strings"
+)
+
+var (
+	// Media type in the Content-Type or Accept header is syntactically invalid.
+	ErrInvalidMediaType = errors.New("Invalid media type")
+	// Range of media types in the Content-Type or Accept header is syntactically invalid.
+	ErrInvalidMediaRange = errors.New("Invalid media range")
+	// Media type parameter in the Content-Type or Accept header is syntactically invalid.
+	ErrInvalidParameter = errors.New("Invalid parameter")
+	// Media type extension parameter in the Content-Type or Accept header is syntactically invalid.
+	ErrInvalidExtensionParameter = errors.New("Invalid extension parameter")
+	// Accept header contains only media types that are not in the acceptable media type list.
+	ErrNoAcceptableTypeFound = errors.New("No acceptable type found")
+	// Acceptbale media type list is empty.
+	ErrNoAvailableTypeGiven = errors.New("No available type given")
+	// Media type weight in the Accept header is syntactically invalid.
+	ErrInvalidWeight = errors.New("Invalid wieght")
+)
+
+// A map for media type parameters.
+type Parameters = map[string]string
+
+// A struct for media type which holds type, subtype, structured syntax suffix
+// (RFC 6839, e.g. "json" for a subtype of "vnd.api+json") and parameters.
+//
+// Parameters are stored in a canonical internal form rather than as a map, so
+// that MediaType itself is comparable with == and can be used as a map key,
+// e.g. by Registry. Use the Parameters method to get the parameters back out.
+type MediaType struct {
+	Type    string
+	Subtype string
+	Suffix  string
+	params  string
+}
+
+// Returns the media type parameters as a map.
+func (mediaType MediaType) Parameters() Parameters {
+	return decodeParameters(mediaType.params)
+}
+
+// Constructs a MediaType from a type, a subtype and parameters, without having
+// to format and parse an intermediate string first.
+func NewMediaTypeWithParameters(t, subtype string, parameters Parameters) MediaType {
+	subtype = strings.ToLower(subtype)
+
+	return MediaType{
+		Type:    strings.ToLower(t),
+		Subtype: subtype,
+		Suffix:  getSuffix(subtype),
+		params:  encodeParameters(parameters),
+	}
+}
+
+// Encodes parameters into a canonical, sorted string so that two MediaType
+// values with the same parameters compare equal regardless of map order.
+// Semicolons and backslashes in values are escaped, since they are used as
+// structural delimiters; parameter names cannot contain either, as they are
+// restricted to RFC 7230 token characters.
+func encodeParameters(parameters Parameters) string {
+	if len(parameters) == 0 {
+		return ""
+	}
+
+	keys := make([]string, 0, len(parameters))
+	for key := range parameters {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var stringBuilder strings.Builder
+	for i, key := range keys {
+		if i > 0 {
+			stringBuilder.WriteByte(';')
+		}
+
+		stringBuilder.WriteString(key)
+		stringBuilder.WriteByte('=')
+		for j := 0; j < len(parameters[key]); j++ {
+			if c := parameters[key][j]; c == '\\' || c == ';' {
+				stringBuilder.WriteByte('\\')
+			}
+			stringBuilder.WriteByte(parameters[key][j])
+		}
+	}
+
+	return stringBuilder.String()
+}
+
+func decodeParameters(s string) Parameters {
+	parameters := make(Parameters)
+
+	var pair strings.Builder
+	flushPair := func() {
+		if raw := pair.String(); len(raw) > 0 {
+			if i := strings.IndexByte(raw, '='); i != -1 {
+				parameters[raw[:i]] = raw[i+1:]
+			}
+		}
+		pair.Reset()
+	}
+
+	for i := 0; i < len(s); i++ {
+		switch s[i] {
+		case '\\':
+			i++
+			if i < len(s) {
+				pair.WriteByte(s[i])
+			}
+		case ';':
+			flushPair()
+		default:
+			pair.WriteByte(s[i])
+		}
+	}
+	flushPair()
+
+	return parameters
+}
+
+func isWhiteSpaceChar(c byte) bool {
+	// RFC 7230, 3.2.3. Whitespace
+	return c == 0x09 || c == 0x20 // HTAB or SP
+}
+
+func isDigitChar(c byte) bool {
+	// RFC 5234, Appendix B.1. Core Rules
+	return c >= 0x30 && c <= 0x39
+}
+
+func isAlphaChar(c byte) bool {
+	// RFC 5234, Appendix B.1. Core Rules
+	return (c >= 0x41 && c <= 0x5A) || (c >= 0x61 && c <= 0x7A)
+}
+
+func isTokenChar(c byte) bool {
+	// RFC 7230, 3.2.6. Field Value Components
+	return c == '!' || c == '#' || c == '$' || c == '%' || c == '&' || c == '\'' || c == '*' ||
+		c == '+' || c == '-' || c == '.' || c == '^' || c == '_' || c == '`' || c == '|' || c == '~' ||
+		isDigitChar(c) ||
+		isAlphaChar(c)
+}
+
+func isVisibleChar(c byte) bool {
+	// RFC 5234, Appendix B.1. Core Rules
+	return c >= 0x21 && c <= 0x7E
+}
+
+func isObsoleteTextChar(c byte) bool {
+	// RFC 7230, 3.2.6. Field Value Components
+	return c >= 0x80 && c <= 0xFF
+}
+
+func isQuotedTextChar(c byte) bool {
+	// RFC 7230, 3.2.6. Field Value Components
+	return c == 0x09 || c == 0x20 || // HTAB or SP
+		c == 0x21 ||
+		(c >= 0x23 && c <= 0x5B) ||
+		(c >= 0x5D && c <= 0x7E) ||
+		isObsoleteTextChar(c)
+}
+
+func isQuotedPairChar(c byte) bool {
+	// RFC 7230, 3.2.6. Field Value Components
+	return c == 0x09 || c == 0x20 || // HTAB or SP
+		isVisibleChar(c) ||
+		isObsoleteTextChar(c)
+}
+
+func skipWhiteSpaces(s string) string {
+	// RFC 7230, 3.2.3. Whitespace
+	for i := 0; i < len(s); i++ {
+		if !isWhiteSpaceChar(s[i]) {
+			return s[i:]
+		}
+	}
+
+	return ""
+}
+
+func consumeToken(s string) (token, remaining string, consumed bool) {
+	// RFC 7230, 3.2.6. Field Value Components
+	for i := 0; i < len(s); i++ {
+		if !isTokenChar(s[i]) {
+			return strings.ToLower(s[:i]), s[i:], i > 0
+		}
+	}
+
+	return strings.ToLower(s), "", len(s) > 0
+}
+
+func consumeQuotedString(s string) (token, remaining string, consumed bool) {
+	var stringBuilder strings.Builder
+
+	index := 0
+	for ; index < len(s); index++ {
+		if s[index] == '\\' {
+			index++
+			if len(s) <= index || !isQuotedPairChar(s[index]) {
+				return "", s, false
+			}
+			stringBuilder.WriteByte(s[index])
+		} else if isQuotedTextChar(s[index]) {
+			stringBuilder.WriteByte(s[index])
+		} else {
+			break
+		}
+	}
+
+	return strings.ToLower(stringBuilder.String()), s[index:], true
+}
+
+func consumeType(s string) (string, string, string, bool) {
+	// RFC 7231, 3.1.1.1. Media Type
+	s = skipWhiteSpaces(s)
+
+	var t, subt string
+	var consumed bool
+	t, s, consumed = consumeToken(s)
+	if !consumed {
+		return "", "", s, false
+	}
+
+	if len(s) == 0 || s[0] != '/' {
+		return "", "", s, false
+	}
+
+	s = s[1:] // skip the slash
+
+	subt, s, consumed = consumeToken(s)
+	if !consumed {
+		return "", "", s, false
+	}
+
+	if t == "*" && subt != "*" {
+		return "", "", s, false
+	}
+
+	s = skipWhiteSpaces(s)
+
+	return t, subt, s, true
+}
+
+func getSuffix(subtype string) string {
+	// RFC 6839, 3. Structured Syntax Suffix Registry
+	if i := strings.LastIndexByte(subtype, '+'); i != -1 {
+		return subtype[i+1:]
+	}
+
+	return ""
+}
+
+func consumeParameter(s string) (string, string, string, bool) {
+	// RFC 7231, 3.1.1.1. Media Type
+	s = skipWhiteSpaces(s)
+
+	var consumed bool
+	var key string
+	key, s, consumed = consumeToken(s)
+	if !consumed {
+		return "", "", s, false
+	}
+
+	if len(s) == 0 || s[0] != '=' {
+		return "", "", s, false
+	}
+
+	s = s[1:] // skip the equal sign
+
+	var value string
+	if len(s) > 0 && s[0] == '"' {
+		s = s[1:] // skip the opening quote
+
+		value, s, consumed = consumeQuotedString(s)
+		if !consumed {
+			return "", "", s, false
+		}
+
+		if len(s) == 0 || s[0] != '"' {
+			return "", "", s, false
+		}
+
+		s = s[1:] // skip the closing quote
+
+	} else {
+		value, s, consumed = consumeToken(s)
+		if !consumed {
+			return "", "", s, false
+		}
+	}
+
+	s = skipWhiteSpaces(s)
+
+	return key, value, s, true
+}
+
+func getWeight(s string) (int, bool) {
+	// RFC 7231, 5.3.1. Quality Values
+	result := 0
+	multiplier := 1000
+
+	// the string must not have more than three digits after the decimal point
+	if len(s) > 5 {
+		return 0, false
+	}
+
+	for i := 0; i < len(s); i++ {
+		if i == 0 {
+			// the first character must be 0 or 1
+			if s[i] != '0' && s[i] != '1' {
+				return 0, false
+			}
+
+			result = int(s[i]-'0') * multiplier
+			multiplier /= 10
+		} else if i == 1 {
+			// the second character must be a dot
+			if s[i] != '.' {
+				return 0, false
+			}
+		} else {
+			// the remaining characters must be digits and the value can not be greater than 1.000
+			if (s[0] == '1' && s[i] != '0') ||
+				(s[i] < '0' || s[i] > '9') {
+				return 0, false
+			}
+
+			result += int(s[i]-'0') * multiplier
+			multiplier /= 10
+		}
+	}
+
+	return result, true
+}
+
+// Match kinds, ordered from the least to the most specific. The zero value, matchNone,
+// means the two media types do not match at all.
+const (
+	matchNone = iota
+	matchWildcardType
+	matchWildcardSubtype
+	matchSuffix
+	matchExact
+)
+
+// Compares the type, subtype and structured syntax suffix of an acceptable media
+// type (checkMediaType) against an available media type (mediaType) and returns
+// how specifically they match, ignoring parameters. A structured syntax suffix
+// (RFC 6839) on checkMediaType, such as the "+json" in "application/vnd.api+json",
+// is allowed to match an available media type whose subtype equals that suffix,
+// e.g. "application/json".
+func compareMediaTypeKind(checkMediaType, mediaType MediaType) int {
+	if checkMediaType.Type != "*" && checkMediaType.Type != mediaType.Type {
+		return matchNone
+	}
+
+	switch {
+	case checkMediaType.Type == "*" && checkMediaType.Subtype == "*":
+		return matchWildcardType
+	case checkMediaType.Subtype == "*":
+		return matchWildcardSubtype
+	case checkMediaType.Subtype != mediaType.Subtype:
+		if checkMediaType.Suffix == "" || checkMediaType.Suffix != mediaType.Subtype {
+			return matchNone
+		}
+		return matchSuffix
+	}
+
+	return matchExact
+}
+
+// Parses the string and returns an instance of MediaType struct.
+func NewMediaType(s string) MediaType {
+	mediaType := MediaType{}
+	var consumed bool
+	mediaType.Type, mediaType.Subtype, s, consumed = consumeType(s)
+	if !consumed {
+		return MediaType{}
+	}
+
+	mediaType.Suffix = getSuffix(mediaType.Subtype)
+	parameters := make(Parameters)
+
+	for len(s) > 0 && s[0] == ';' {
+		s = s[1:] // skip the semicolon
+
+		key, value, remaining, consumed := consumeParameter(s)
+		if !consumed {
+			return MediaType{}
+		}
+
+		s = remaining
+
+		parameters[key] = value
+	}
+
+	mediaType.params = encodeParameters(parameters)
+
+	return mediaType
+}
+
+// Converts the MediaType to string.
+func (mediaType *MediaType) String() string {
+	var stringBuilder strings.Builder
+
+	if len(mediaType.Type) > 0 || len(mediaType.Subtype) > 0 {
+		stringBuilder.WriteString(mediaType.Type)
+		stringBuilder.WriteByte('/')
+		stringBuilder.WriteString(mediaType.Subtype)
+	}
+
+	parameters := mediaType.Parameters()
+	keys := make([]string, 0, len(parameters))
+	for key := range parameters {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		stringBuilder.WriteByte(';')
+		stringBuilder.WriteString(key)
+		stringBuilder.WriteByte('=')
+		stringBuilder.WriteString(parameters[key])
+	}
+
+	return stringBuilder.String()
+}
+
+// Gets the content of Content-Type header, parses it, and returns the parsed MediaType.
+// If the request does not contain the Content-Type header, an empty MediaType is returned.
+func GetMediaType(request *http.Request) (MediaType, error) {
+	// RFC 7231, 3.1.1.5. Content-Type
+	contentTypeHeaders := request.Header.Values("Content-Type")
+	if len(contentTypeHeaders) == 0 {
+		return MediaType{}, nil
+	}
+
+	s := contentTypeHeaders[0]
+	mediaType := MediaType{}
+	var consumed bool
+	mediaType.Type, mediaType.Subtype, s, consumed = consumeType(s)
+	if !consumed {
+		return MediaType{}, ErrInvalidMediaType
+	}
+
+	mediaType.Suffix = getSuffix(mediaType.Subtype)
+	parameters := make(Parameters)
+
+	for len(s) > 0 && s[0] == ';' {
+		s = s[1:] // skip the semicolon
+
+		key, value, remaining, consumed := consumeParameter(s)
+		if !consumed {
+			return MediaType{}, ErrInvalidParameter
+		}
+
+		s = remaining
+
+		parameters[key] = value
+	}
+
+	// there must not be anything left after parsing the header
+	if len(s) > 0 {
+		return MediaType{}, ErrInvalidMediaType
+	}
+
+	mediaType.params = encodeParameters(parameters)
+
+	return mediaType, nil
+}
+
+// Choses a media type from available media types according to the Accept.
+// Returns the most suitable media type or an error if no type can be selected.
+// An available media type whose subtype equals the structured syntax suffix
+// (RFC 6839) of an acceptable media type, e.g. "application/json" for an Accept
+// entry of "application/vnd.api+json", is matched once no exact match is found.
+// Equivalent to GetAcceptableMediaTypeWithOptions with the zero MatchOptions.
+func GetAcceptableMediaType(request *http.Request, availableMediaTypes []MediaType) (MediaType, Parameters, error) {
+	return GetAcceptableMediaTypeWithOptions(request, availableMediaTypes, MatchOptions{})
+}
@@ -13,20 +13,22 @@ func TestNewMediaType(t *testing.T) {
 		result MediaType
 	}{
 		{"", MediaType{}},
-		{"application/json", MediaType{"application", "json", Parameters{}}},
-		{"a/b;c=d", MediaType{"a", "b", Parameters{"c": "d"}}},
+		{"application/json", NewMediaTypeWithParameters("application", "json", Parameters{})},
+		{"a/b;c=d", NewMediaTypeWithParameters("a", "b", Parameters{"c": "d"})},
 		{"/b", MediaType{}},
 		{"a/", MediaType{}},
 		{"a/b;c", MediaType{}},
+		{"application/vnd.docker.distribution.manifest.v2+json", NewMediaTypeWithParameters("application", "vnd.docker.distribution.manifest.v2+json", Parameters{})},
+		{"application/json", NewMediaTypeWithParameters("application", "json", Parameters{})},
 	}
 
 	for _, testCase := range testCases {
 		result := NewMediaType(testCase.value)
 
-		if result.Type != testCase.result.Type || result.Subtype != testCase.result.Subtype {
-			t.Errorf("Invalid content type, got %s/%s, exptected %s/%s for %s", result.Type, result.Subtype, testCase.result.Type, testCase.result.Subtype, testCase.value)
-		} else if !reflect.DeepEqual(result.Parameters, testCase.result.Parameters) {
-			t.Errorf("Wrong parameters, got %v, expected %v for %s", result.Parameters, testCase.result.Parameters, testCase.value)
+		if result.Type != testCase.result.Type || result.Subtype != testCase.result.Subtype || result.Suffix != testCase.result.Suffix {
+			t.Errorf("Invalid content type, got %s/%s+%s, exptected %s/%s+%s for %s", result.Type, result.Subtype, result.Suffix, testCase.result.Type, testCase.result.Subtype, testCase.result.Suffix, testCase.value)
+		} else if !reflect.DeepEqual(result.Parameters(), testCase.result.Parameters()) {
+			t.Errorf("Wrong parameters, got %v, expected %v for %s", result.Parameters(), testCase.result.Parameters(), testCase.value)
 		}
 	}
 }
@@ -37,15 +39,18 @@ func TestString(t *testing.T) {
 		result string
 	}{
 		{MediaType{}, ""},
-		{MediaType{"application", "json", Parameters{}}, "application/json"},
-		{MediaType{"a", "b", Parameters{"c": "d"}}, "a/b;c=d"},
+		{NewMediaTypeWithParameters("application", "json", Parameters{}), "application/json"},
+		{NewMediaTypeWithParameters("a", "b", Parameters{"c": "d"}), "a/b;c=d"},
+		{NewMediaTypeWithParameters("a", "b", Parameters{"z": "1", "a": "2", "m": "3"}), "a/b;a=2;m=3;z=1"},
 	}
 
 	for _, testCase := range testCases {
-		result := testCase.value.String()
+		for i := 0; i < 10; i++ {
+			result := testCase.value.String()
 
-		if result != testCase.result {
-			t.Errorf("Invalid result type, got %s, exptected %s", result, testCase.result)
+			if result != testCase.result {
+				t.Errorf("Invalid result type, got %s, exptected %s", result, testCase.result)
+			}
 		}
 	}
 }
@@ -56,19 +61,19 @@ func TestGetMediaType(t *testing.T) {
 		result MediaType
 	}{
 		{"", MediaType{}},
-		{"application/json", MediaType{"application", "json", Parameters{}}},
-		{"*/*", MediaType{"*", "*", Parameters{}}},
-		{"Application/JSON", MediaType{"application", "json", Parameters{}}},
-		{" application/json ", MediaType{"application", "json", Parameters{}}},
-		{"Application/XML;charset=utf-8", MediaType{"application", "xml", Parameters{"charset": "utf-8"}}},
-		{"application/xml;foo=bar ", MediaType{"application", "xml", Parameters{"foo": "bar"}}},
-		{"application/xml ; foo=bar ", MediaType{"application", "xml", Parameters{"foo": "bar"}}},
-		{"application/xml;foo=\"bar\" ", MediaType{"application", "xml", Parameters{"foo": "bar"}}},
-		{"application/xml;foo=\"\" ", MediaType{"application", "xml", Parameters{"foo": ""}}},
-		{"application/xml;foo=\"\\\"b\" ", MediaType{"application", "xml", Parameters{"foo": "\"b"}}},
-		{"application/xml;foo=\"\\\"B\" ", MediaType{"application", "xml", Parameters{"foo": "\"b"}}},
-		{"a/b+c;a=b;c=d", MediaType{"a", "b+c", Parameters{"a": "b", "c": "d"}}},
-		{"a/b;A=B", MediaType{"a", "b", Parameters{"a": "b"}}},
+		{"application/json", NewMediaTypeWithParameters("application", "json", Parameters{})},
+		{"*/*", NewMediaTypeWithParameters("*", "*", Parameters{})},
+		{"Application/JSON", NewMediaTypeWithParameters("application", "json", Parameters{})},
+		{" application/json ", NewMediaTypeWithParameters("application", "json", Parameters{})},
+		{"Application/XML;charset=utf-8", NewMediaTypeWithParameters("application", "xml", Parameters{"charset": "utf-8"})},
+		{"application/xml;foo=bar ", NewMediaTypeWithParameters("application", "xml", Parameters{"foo": "bar"})},
+		{"application/xml ; foo=bar ", NewMediaTypeWithParameters("application", "xml", Parameters{"foo": "bar"})},
+		{"application/xml;foo=\"bar\" ", NewMediaTypeWithParameters("application", "xml", Parameters{"foo": "bar"})},
+		{"application/xml;foo=\"\" ", NewMediaTypeWithParameters("application", "xml", Parameters{"foo": ""})},
+		{"application/xml;foo=\"\\\"b\" ", NewMediaTypeWithParameters("application", "xml", Parameters{"foo": "\"b"})},
+		{"application/xml;foo=\"\\\"B\" ", NewMediaTypeWithParameters("application", "xml", Parameters{"foo": "\"b"})},
+		{"a/b+c;a=b;c=d", NewMediaTypeWithParameters("a", "b+c", Parameters{"a": "b", "c": "d"})},
+		{"a/b;A=B", NewMediaTypeWithParameters("a", "b", Parameters{"a": "b"})},
 	}
 
 	for _, testCase := range testCases {
@@ -86,8 +91,8 @@ func TestGetMediaType(t *testing.T) {
 			t.Errorf("Unexpected error \"%s\" for %s", mediaTypeError.Error(), testCase.header)
 		} else if result.Type != testCase.result.Type || result.Subtype != testCase.result.Subtype {
 			t.Errorf("Invalid content type, got %s/%s, exptected %s/%s for %s", result.Type, result.Subtype, testCase.result.Type, testCase.result.Subtype, testCase.header)
-		} else if !reflect.DeepEqual(result.Parameters, testCase.result.Parameters) {
-			t.Errorf("Wrong parameters, got %v, expected %v for %s", result.Parameters, testCase.result.Parameters, testCase.header)
+		} else if !reflect.DeepEqual(result.Parameters(), testCase.result.Parameters()) {
+			t.Errorf("Wrong parameters, got %v, expected %v for %s", result.Parameters(), testCase.result.Parameters(), testCase.header)
 		}
 	}
 }
@@ -138,52 +143,61 @@ func TestGetAcceptableMediaType(t *testing.T) {
 		result              MediaType
 		extensionParameters Parameters
 	}{
-		{"", []MediaType{{"application", "json", Parameters{}}}, MediaType{"application", "json", Parameters{}}, Parameters{}},
-		{"application/json", []MediaType{{"application", "json", Parameters{}}}, MediaType{"application", "json", Parameters{}}, Parameters{}},
-		{"Application/Json", []MediaType{{"application", "json", Parameters{}}}, MediaType{"application", "json", Parameters{}}, Parameters{}},
-		{"text/plain,application/xml", []MediaType{{"text", "plain", Parameters{}}}, MediaType{"text", "plain", Parameters{}}, Parameters{}},
-		{"text/plain,application/xml", []MediaType{{"application", "xml", Parameters{}}}, MediaType{"application", "xml", Parameters{}}, Parameters{}},
-		{"text/plain;q=1.0", []MediaType{{"text", "plain", Parameters{}}}, MediaType{"text", "plain", Parameters{}}, Parameters{}},
-		{"*/*", []MediaType{{"application", "json", Parameters{}}}, MediaType{"application", "json", Parameters{}}, Parameters{}},
-		{"application/*", []MediaType{{"application", "json", Parameters{}}}, MediaType{"application", "json", Parameters{}}, Parameters{}},
-		{"a/b;q=1.", []MediaType{{"a", "b", Parameters{}}}, MediaType{"a", "b", Parameters{}}, Parameters{}},
+		{"", []MediaType{NewMediaTypeWithParameters("application", "json", Parameters{})}, NewMediaTypeWithParameters("application", "json", Parameters{}), Parameters{}},
+		{"application/json", []MediaType{NewMediaTypeWithParameters("application", "json", Parameters{})}, NewMediaTypeWithParameters("application", "json", Parameters{}), Parameters{}},
+		{"Application/Json", []MediaType{NewMediaTypeWithParameters("application", "json", Parameters{})}, NewMediaTypeWithParameters("application", "json", Parameters{}), Parameters{}},
+		{"text/plain,application/xml", []MediaType{NewMediaTypeWithParameters("text", "plain", Parameters{})}, NewMediaTypeWithParameters("text", "plain", Parameters{}), Parameters{}},
+		{"text/plain,application/xml", []MediaType{NewMediaTypeWithParameters("application", "xml", Parameters{})}, NewMediaTypeWithParameters("application", "xml", Parameters{}), Parameters{}},
+		{"text/plain;q=1.0", []MediaType{NewMediaTypeWithParameters("text", "plain", Parameters{})}, NewMediaTypeWithParameters("text", "plain", Parameters{}), Parameters{}},
+		{"*/*", []MediaType{NewMediaTypeWithParameters("application", "json", Parameters{})}, NewMediaTypeWithParameters("application", "json", Parameters{}), Parameters{}},
+		{"application/*", []MediaType{NewMediaTypeWithParameters("application", "json", Parameters{})}, NewMediaTypeWithParameters("application", "json", Parameters{}), Parameters{}},
+		{"a/b;q=1.", []MediaType{NewMediaTypeWithParameters("a", "b", Parameters{})}, NewMediaTypeWithParameters("a", "b", Parameters{}), Parameters{}},
 		{"a/b;q=0.1,c/d;q=0.2", []MediaType{
-			{"a", "b", Parameters{}},
-			{"c", "d", Parameters{}},
-		}, MediaType{"c", "d", Parameters{}}, Parameters{}},
+			NewMediaTypeWithParameters("a", "b", Parameters{}),
+			NewMediaTypeWithParameters("c", "d", Parameters{}),
+		}, NewMediaTypeWithParameters("c", "d", Parameters{}), Parameters{}},
 		{"a/b;q=0.2,c/d;q=0.2", []MediaType{
-			{"a", "b", Parameters{}},
-			{"c", "d", Parameters{}},
-		}, MediaType{"a", "b", Parameters{}}, Parameters{}},
+			NewMediaTypeWithParameters("a", "b", Parameters{}),
+			NewMediaTypeWithParameters("c", "d", Parameters{}),
+		}, NewMediaTypeWithParameters("a", "b", Parameters{}), Parameters{}},
 		{"a/*;q=0.2,a/c", []MediaType{
-			{"a", "b", Parameters{}},
-			{"a", "c", Parameters{}},
-		}, MediaType{"a", "c", Parameters{}}, Parameters{}},
+			NewMediaTypeWithParameters("a", "b", Parameters{}),
+			NewMediaTypeWithParameters("a", "c", Parameters{}),
+		}, NewMediaTypeWithParameters("a", "c", Parameters{}), Parameters{}},
 		{"a/b,a/a", []MediaType{
-			{"a", "a", Parameters{}},
-			{"a", "b", Parameters{}},
-		}, MediaType{"a", "b", Parameters{}}, Parameters{}},
+			NewMediaTypeWithParameters("a", "a", Parameters{}),
+			NewMediaTypeWithParameters("a", "b", Parameters{}),
+		}, NewMediaTypeWithParameters("a", "b", Parameters{}), Parameters{}},
 		{"a/*", []MediaType{
-			{"a", "a", Parameters{}},
-			{"a", "b", Parameters{}},
-		}, MediaType{"a", "a", Parameters{}}, Parameters{}},
+			NewMediaTypeWithParameters("a", "a", Parameters{}),
+			NewMediaTypeWithParameters("a", "b", Parameters{}),
+		}, NewMediaTypeWithParameters("a", "a", Parameters{}), Parameters{}},
 		{"a/a;q=0.2,a/*", []MediaType{
-			{"a", "a", Parameters{}},
-			{"a", "b", Parameters{}},
-		}, MediaType{"a", "b", Parameters{}}, Parameters{}},
+			NewMediaTypeWithParameters("a", "a", Parameters{}),
+			NewMediaTypeWithParameters("a", "b", Parameters{}),
+		}, NewMediaTypeWithParameters("a", "b", Parameters{}), Parameters{}},
 		{"a/a;q=0.2,a/a;c=d", []MediaType{
-			{"a", "a", Parameters{}},
-			{"a", "a", Parameters{"c": "d"}},
-		}, MediaType{"a", "a", Parameters{"c": "d"}}, Parameters{}},
-		{"a/b;q=1;e=e", []MediaType{{"a", "b", Parameters{}}}, MediaType{"a", "b", Parameters{}}, Parameters{"e": "e"}},
+			NewMediaTypeWithParameters("a", "a", Parameters{}),
+			NewMediaTypeWithParameters("a", "a", Parameters{"c": "d"}),
+		}, NewMediaTypeWithParameters("a", "a", Parameters{"c": "d"}), Parameters{}},
+		{"a/b;q=1;e=e", []MediaType{NewMediaTypeWithParameters("a", "b", Parameters{})}, NewMediaTypeWithParameters("a", "b", Parameters{}), Parameters{"e": "e"}},
 		{"a/*,a/a;q=0", []MediaType{
-			{"a", "a", Parameters{}},
-			{"a", "b", Parameters{}},
-		}, MediaType{"a", "b", Parameters{}}, Parameters{}},
+			NewMediaTypeWithParameters("a", "a", Parameters{}),
+			NewMediaTypeWithParameters("a", "b", Parameters{}),
+		}, NewMediaTypeWithParameters("a", "b", Parameters{}), Parameters{}},
 		{"a/a;q=0.001,a/b;q=0.002", []MediaType{
-			{"a", "a", Parameters{}},
-			{"a", "b", Parameters{}},
-		}, MediaType{"a", "b", Parameters{}}, Parameters{}},
+			NewMediaTypeWithParameters("a", "a", Parameters{}),
+			NewMediaTypeWithParameters("a", "b", Parameters{}),
+		}, NewMediaTypeWithParameters("a", "b", Parameters{}), Parameters{}},
+		{"application/vnd.docker.distribution.manifest.v2+json", []MediaType{
+			NewMediaTypeWithParameters("application", "json", Parameters{}),
+		}, NewMediaTypeWithParameters("application", "vnd.docker.distribution.manifest.v2+json", Parameters{}), Parameters{}},
+		{"application/vnd.api+json,application/json", []MediaType{
+			NewMediaTypeWithParameters("application", "json", Parameters{}),
+		}, NewMediaTypeWithParameters("application", "json", Parameters{}), Parameters{}},
+		{"application/vnd.api+json;q=0.5,*/*", []MediaType{
+			NewMediaTypeWithParameters("application", "json", Parameters{}),
+		}, NewMediaTypeWithParameters("application", "vnd.api+json", Parameters{}), Parameters{}},
 	}
 
 	for _, testCase := range testCases {
@@ -202,8 +216,8 @@ func TestGetAcceptableMediaType(t *testing.T) {
 			t.Errorf("Unexpected error \"%s\" for %s", mediaTypeError.Error(), testCase.header)
 		} else if result.Type != testCase.result.Type || result.Subtype != testCase.result.Subtype {
 			t.Errorf("Invalid content type, got %s/%s, exptected %s/%s for %s", result.Type, result.Subtype, testCase.result.Type, testCase.result.Subtype, testCase.header)
-		} else if !reflect.DeepEqual(result.Parameters, testCase.result.Parameters) {
-			t.Errorf("Wrong parameters, got %v, expected %v for %s", result.Parameters, testCase.result.Parameters, testCase.header)
+		} else if !reflect.DeepEqual(result.Parameters(), testCase.result.Parameters()) {
+			t.Errorf("Wrong parameters, got %v, expected %v for %s", result.Parameters(), testCase.result.Parameters(), testCase.header)
 		} else if !reflect.DeepEqual(extensionParameters, testCase.extensionParameters) {
 			t.Errorf("Wrong extension parameters, got %v, expected %v for %s", extensionParameters, testCase.extensionParameters, testCase.header)
 		}
@@ -217,21 +231,21 @@ func TestGetAcceptableMediaTypeErrors(t *testing.T) {
 		err                 error
 	}{
 		{"", []MediaType{}, ErrNoAvailableTypeGiven},
-		{"application/xml", []MediaType{{"application", "json", Parameters{}}}, ErrNoAcceptableTypeFound},
-		{"application/xml/", []MediaType{{"application", "json", Parameters{}}}, ErrInvalidMediaRange},
-		{"application/xml,", []MediaType{{"application", "json", Parameters{}}}, ErrInvalidMediaType},
-		{"/xml", []MediaType{{"application", "json", Parameters{}}}, ErrInvalidMediaType},
-		{"application/,", []MediaType{{"application", "json", Parameters{}}}, ErrInvalidMediaType},
-		{"a/b c", []MediaType{{"a", "b", Parameters{}}}, ErrInvalidMediaRange},
-		{"a/b;c", []MediaType{{"a", "b", Parameters{}}}, ErrInvalidParameter},
-		{"*/b", []MediaType{{"a", "b", Parameters{}}}, ErrInvalidMediaType},
-		{"a/b;q=a", []MediaType{{"a", "b", Parameters{}}}, ErrInvalidWeight},
-		{"a/b;q=11", []MediaType{{"a", "b", Parameters{}}}, ErrInvalidWeight},
-		{"a/b;q=1.0000", []MediaType{{"a", "b", Parameters{}}}, ErrInvalidWeight},
-		{"a/b;q=1.a", []MediaType{{"a", "b", Parameters{}}}, ErrInvalidWeight},
-		{"a/b;q=1.100", []MediaType{{"a", "b", Parameters{}}}, ErrInvalidWeight},
-		{"a/b;q=0", []MediaType{{"a", "b", Parameters{}}}, ErrNoAcceptableTypeFound},
-		{"a/a;q=1;ext=", []MediaType{{"a", "a", Parameters{}}}, ErrInvalidParameter},
+		{"application/xml", []MediaType{NewMediaTypeWithParameters("application", "json", Parameters{})}, ErrNoAcceptableTypeFound},
+		{"application/xml/", []MediaType{NewMediaTypeWithParameters("application", "json", Parameters{})}, ErrInvalidMediaRange},
+		{"application/xml,", []MediaType{NewMediaTypeWithParameters("application", "json", Parameters{})}, ErrInvalidMediaType},
+		{"/xml", []MediaType{NewMediaTypeWithParameters("application", "json", Parameters{})}, ErrInvalidMediaType},
+		{"application/,", []MediaType{NewMediaTypeWithParameters("application", "json", Parameters{})}, ErrInvalidMediaType},
+		{"a/b c", []MediaType{NewMediaTypeWithParameters("a", "b", Parameters{})}, ErrInvalidMediaRange},
+		{"a/b;c", []MediaType{NewMediaTypeWithParameters("a", "b", Parameters{})}, ErrInvalidParameter},
+		{"*/b", []MediaType{NewMediaTypeWithParameters("a", "b", Parameters{})}, ErrInvalidMediaType},
+		{"a/b;q=a", []MediaType{NewMediaTypeWithParameters("a", "b", Parameters{})}, ErrInvalidWeight},
+		{"a/b;q=11", []MediaType{NewMediaTypeWithParameters("a", "b", Parameters{})}, ErrInvalidWeight},
+		{"a/b;q=1.0000", []MediaType{NewMediaTypeWithParameters("a", "b", Parameters{})}, ErrInvalidWeight},
+		{"a/b;q=1.a", []MediaType{NewMediaTypeWithParameters("a", "b", Parameters{})}, ErrInvalidWeight},
+		{"a/b;q=1.100", []MediaType{NewMediaTypeWithParameters("a", "b", Parameters{})}, ErrInvalidWeight},
+		{"a/b;q=0", []MediaType{NewMediaTypeWithParameters("a", "b", Parameters{})}, ErrNoAcceptableTypeFound},
+		{"a/a;q=1;ext=", []MediaType{NewMediaTypeWithParameters("a", "a", Parameters{})}, ErrInvalidParameter},
 	}
 
 	for _, testCase := range testCases {
@@ -0,0 +1,64 @@
+package contenttype
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+)
+
+var (
+	// Encoding in the Accept-Encoding header is syntactically invalid.
+	ErrInvalidEncoding = errors.New("Invalid encoding")
+	// Accept-Encoding header contains only encodings that are not in the acceptable encoding list.
+	ErrNoAcceptableEncodingFound = errors.New("No acceptable encoding found")
+	// Acceptable encoding list is empty.
+	ErrNoAvailableEncodingGiven = errors.New("No available encoding given")
+)
+
+// Choses a content coding from available encodings according to the Accept-Encoding header.
+// Returns the most suitable encoding or an error if no encoding can be selected.
+// As required by RFC 9110, 12.5.3, "identity" is treated as acceptable unless it is
+// explicitly refused with a weight of 0, and "*" applies to any encoding not otherwise
+// mentioned in the header.
+func GetAcceptableEncoding(request *http.Request, availableEncodings []string) (string, error) {
+	if len(availableEncodings) == 0 {
+		return "", ErrNoAvailableEncodingGiven
+	}
+
+	acceptEncodingHeaders := request.Header.Values("Accept-Encoding")
+	if len(acceptEncodingHeaders) == 0 {
+		return availableEncodings[0], nil
+	}
+
+	items, err := parseWeightedList(acceptEncodingHeaders[0], ErrInvalidEncoding)
+	if err != nil {
+		return "", err
+	}
+
+	matches := make([]weightedMatch, len(availableEncodings))
+
+	for i, encoding := range availableEncodings {
+		if strings.EqualFold(encoding, "identity") {
+			matches[i].record(1000, len(items), false) // implicitly acceptable, lowest precedence
+		}
+	}
+
+	for order, item := range items {
+		for i, encoding := range availableEncodings {
+			// RFC 9110, 12.5.3: content codings are case-insensitive
+			exact := strings.EqualFold(item.value, encoding)
+			if !exact && item.value != "*" {
+				continue
+			}
+
+			matches[i].record(item.weight, order, exact)
+		}
+	}
+
+	resultIndex := bestMatch(matches)
+	if resultIndex == -1 {
+		return "", ErrNoAcceptableEncodingFound
+	}
+
+	return availableEncodings[resultIndex], nil
+}
@@ -0,0 +1,77 @@
+package contenttype
+
+import (
+	"log"
+	"net/http"
+	"testing"
+)
+
+func TestGetAcceptableEncoding(t *testing.T) {
+	testCases := []struct {
+		header             string
+		availableEncodings []string
+		result             string
+	}{
+		{"", []string{"gzip"}, "gzip"},
+		{"gzip", []string{"gzip"}, "gzip"},
+		{"gzip;q=0.5,br", []string{"gzip", "br"}, "br"},
+		{"*", []string{"gzip"}, "gzip"},
+		{"identity", []string{"identity", "gzip"}, "identity"},
+		{"gzip", []string{"identity", "gzip"}, "gzip"},
+		{"gzip;q=0.5", []string{"identity", "gzip"}, "identity"},
+		{"identity;q=0,gzip", []string{"identity", "gzip"}, "gzip"},
+		{"gzip", []string{"GZIP"}, "GZIP"},
+		{"identity;q=0,gzip", []string{"Identity", "gzip"}, "gzip"},
+	}
+
+	for _, testCase := range testCases {
+		request, requestError := http.NewRequest(http.MethodGet, "http://test.test", nil)
+		if requestError != nil {
+			log.Fatal(requestError)
+		}
+
+		if len(testCase.header) > 0 {
+			request.Header.Set("Accept-Encoding", testCase.header)
+		}
+
+		result, encodingError := GetAcceptableEncoding(request, testCase.availableEncodings)
+		if encodingError != nil {
+			t.Errorf("Unexpected error \"%s\" for %s", encodingError.Error(), testCase.header)
+		} else if result != testCase.result {
+			t.Errorf("Invalid encoding, got %s, expected %s for %s", result, testCase.result, testCase.header)
+		}
+	}
+}
+
+func TestGetAcceptableEncodingErrors(t *testing.T) {
+	testCases := []struct {
+		header             string
+		availableEncodings []string
+		err                error
+	}{
+		{"", []string{}, ErrNoAvailableEncodingGiven},
+		{"gzip", []string{"br"}, ErrNoAcceptableEncodingFound},
+		{"gzip;q=0", []string{"gzip"}, ErrNoAcceptableEncodingFound},
+		{"gzip,", []string{"gzip"}, ErrInvalidEncoding},
+		{"gzip;q=a", []string{"gzip"}, ErrInvalidWeight},
+		{"gzip;br", []string{"gzip"}, ErrInvalidEncoding},
+	}
+
+	for _, testCase := range testCases {
+		request, requestError := http.NewRequest(http.MethodGet, "http://test.test", nil)
+		if requestError != nil {
+			log.Fatal(requestError)
+		}
+
+		if len(testCase.header) > 0 {
+			request.Header.Set("Accept-Encoding", testCase.header)
+		}
+
+		_, encodingError := GetAcceptableEncoding(request, testCase.availableEncodings)
+		if encodingError == nil {
+			t.Errorf("Expected an error for %s", testCase.header)
+		} else if testCase.err != encodingError {
+			t.Errorf("Unexpected error \"%s\", expected \"%s\" for %s", encodingError.Error(), testCase.err.Error(), testCase.header)
+		}
+	}
+}
@@ -0,0 +1,84 @@
+package contenttype
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"golang.org/x/text/language"
+)
+
+var (
+	// Language range in the Accept-Language header is syntactically invalid.
+	ErrInvalidLanguage = errors.New("Invalid language")
+	// Accept-Language header contains only languages that are not in the acceptable language list.
+	ErrNoAcceptableLanguageFound = errors.New("No acceptable language found")
+	// Acceptable language list is empty.
+	ErrNoAvailableLanguageGiven = errors.New("No available language given")
+)
+
+// Reports whether the BCP 47 language range matches the available tag, either
+// exactly or as a prefix (RFC 9110, 12.5.4), e.g. the range "en" matches the
+// tag "en-US".
+func languageRangeMatches(rangeTag, availableTag string) bool {
+	if strings.EqualFold(rangeTag, availableTag) {
+		return true
+	}
+
+	return len(rangeTag) < len(availableTag) &&
+		strings.EqualFold(availableTag[:len(rangeTag)], rangeTag) &&
+		availableTag[len(rangeTag)] == '-'
+}
+
+// Choses a language tag from available language tags according to the Accept-Language header.
+// Returns the most suitable tag or an error if no tag can be selected.
+func GetAcceptableLanguage(request *http.Request, availableLanguages []language.Tag) (language.Tag, error) {
+	if len(availableLanguages) == 0 {
+		return language.Tag{}, ErrNoAvailableLanguageGiven
+	}
+
+	acceptLanguageHeaders := request.Header.Values("Accept-Language")
+	if len(acceptLanguageHeaders) == 0 {
+		return availableLanguages[0], nil
+	}
+
+	items, err := parseWeightedList(acceptLanguageHeaders[0], ErrInvalidLanguage)
+	if err != nil {
+		return language.Tag{}, err
+	}
+
+	availableTagStrings := make([]string, len(availableLanguages))
+	for i, availableLanguage := range availableLanguages {
+		availableTagStrings[i] = availableLanguage.String()
+	}
+
+	matches := make([]weightedMatch, len(availableLanguages))
+
+	for order, item := range items {
+		if item.value != "*" {
+			if _, err := language.Parse(item.value); err != nil {
+				return language.Tag{}, ErrInvalidLanguage
+			}
+		}
+
+		for i, availableTagString := range availableTagStrings {
+			if item.value == "*" {
+				matches[i].record(item.weight, order, false)
+				continue
+			}
+
+			if !languageRangeMatches(item.value, availableTagString) {
+				continue
+			}
+
+			matches[i].record(item.weight, order, strings.EqualFold(item.value, availableTagString))
+		}
+	}
+
+	resultIndex := bestMatch(matches)
+	if resultIndex == -1 {
+		return language.Tag{}, ErrNoAcceptableLanguageFound
+	}
+
+	return availableLanguages[resultIndex], nil
+}
@@ -0,0 +1,74 @@
+package contenttype
+
+import (
+	"log"
+	"net/http"
+	"testing"
+
+	"golang.org/x/text/language"
+)
+
+func TestGetAcceptableLanguage(t *testing.T) {
+	testCases := []struct {
+		header             string
+		availableLanguages []language.Tag
+		result             language.Tag
+	}{
+		{"", []language.Tag{language.AmericanEnglish}, language.AmericanEnglish},
+		{"en-US", []language.Tag{language.AmericanEnglish}, language.AmericanEnglish},
+		{"en", []language.Tag{language.AmericanEnglish}, language.AmericanEnglish},
+		{"fr;q=0.5,en", []language.Tag{language.French, language.English}, language.English},
+		{"*", []language.Tag{language.French}, language.French},
+		{"en", []language.Tag{language.AmericanEnglish, language.English}, language.English},
+	}
+
+	for _, testCase := range testCases {
+		request, requestError := http.NewRequest(http.MethodGet, "http://test.test", nil)
+		if requestError != nil {
+			log.Fatal(requestError)
+		}
+
+		if len(testCase.header) > 0 {
+			request.Header.Set("Accept-Language", testCase.header)
+		}
+
+		result, languageError := GetAcceptableLanguage(request, testCase.availableLanguages)
+		if languageError != nil {
+			t.Errorf("Unexpected error \"%s\" for %s", languageError.Error(), testCase.header)
+		} else if result != testCase.result {
+			t.Errorf("Invalid language, got %s, expected %s for %s", result, testCase.result, testCase.header)
+		}
+	}
+}
+
+func TestGetAcceptableLanguageErrors(t *testing.T) {
+	testCases := []struct {
+		header             string
+		availableLanguages []language.Tag
+		err                error
+	}{
+		{"", []language.Tag{}, ErrNoAvailableLanguageGiven},
+		{"fr", []language.Tag{language.English}, ErrNoAcceptableLanguageFound},
+		{"en;q=0", []language.Tag{language.English}, ErrNoAcceptableLanguageFound},
+		{"en,", []language.Tag{language.English}, ErrInvalidLanguage},
+		{"en;q=a", []language.Tag{language.English}, ErrInvalidWeight},
+	}
+
+	for _, testCase := range testCases {
+		request, requestError := http.NewRequest(http.MethodGet, "http://test.test", nil)
+		if requestError != nil {
+			log.Fatal(requestError)
+		}
+
+		if len(testCase.header) > 0 {
+			request.Header.Set("Accept-Language", testCase.header)
+		}
+
+		_, languageError := GetAcceptableLanguage(request, testCase.availableLanguages)
+		if languageError == nil {
+			t.Errorf("Expected an error for %s", testCase.header)
+		} else if testCase.err != languageError {
+			t.Errorf("Unexpected error \"%s\", expected \"%s\" for %s", languageError.Error(), testCase.err.Error(), testCase.header)
+		}
+	}
+}
@@ -0,0 +1,233 @@
+package contenttype
+
+import "net/http"
+
+// Configures how GetAcceptableMediaTypeWithOptions compares the parameters of
+// an Accept entry against the parameters of an available media type. The zero
+// value reproduces the behavior of GetAcceptableMediaType: an Accept entry
+// matches an available media type only if every parameter it specifies is
+// also present, with the same value, on the available media type.
+type MatchOptions struct {
+	// Parameter names excluded from comparison in both directions, e.g.
+	// "charset" or "boundary", so that an Accept entry such as
+	// "application/json;charset=utf-8" still matches an available media type
+	// of "application/json".
+	IgnoreParameters []string
+	// When true, every parameter of an available media type (other than those
+	// in IgnoreParameters) must also be specified, with the same value, by the
+	// Accept entry. When false, parameters of the available media type that
+	// the Accept entry does not mention are treated as preferences rather than
+	// requirements.
+	RequireAllAvailableParams bool
+	// Name of a parameter, such as "profile", whose presence with the same
+	// value on both the Accept entry and the available media type promotes
+	// the match above a bare subtype match that does not share it.
+	ProfileParam string
+}
+
+func ignores(name string, ignoreParameters []string) bool {
+	for _, ignored := range ignoreParameters {
+		if ignored == name {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Tracks how specifically an Accept entry matches an available media type:
+// the type/subtype/suffix kind (see compareMediaTypeKind) and, when
+// options.ProfileParam is set, whether both sides agree on its value.
+type mediaTypeMatch struct {
+	kind    int
+	profile bool
+}
+
+func compareMediaTypesWithOptions(checkMediaType, mediaType MediaType, options MatchOptions) mediaTypeMatch {
+	kind := compareMediaTypeKind(checkMediaType, mediaType)
+	if kind == matchNone {
+		return mediaTypeMatch{kind: matchNone}
+	}
+
+	checkParameters := checkMediaType.Parameters()
+	mediaTypeParameters := mediaType.Parameters()
+
+	for checkKey, checkValue := range checkParameters {
+		if checkKey == options.ProfileParam || ignores(checkKey, options.IgnoreParameters) {
+			continue
+		}
+
+		if value, found := mediaTypeParameters[checkKey]; !found || value != checkValue {
+			return mediaTypeMatch{kind: matchNone}
+		}
+	}
+
+	if options.RequireAllAvailableParams {
+		for availableKey, availableValue := range mediaTypeParameters {
+			if availableKey == options.ProfileParam || ignores(availableKey, options.IgnoreParameters) {
+				continue
+			}
+
+			if value, found := checkParameters[availableKey]; !found || value != availableValue {
+				return mediaTypeMatch{kind: matchNone}
+			}
+		}
+	}
+
+	profile := false
+	if options.ProfileParam != "" {
+		checkProfile, checkHasProfile := checkParameters[options.ProfileParam]
+		availableProfile, availableHasProfile := mediaTypeParameters[options.ProfileParam]
+
+		if checkHasProfile && availableHasProfile {
+			if checkProfile != availableProfile {
+				return mediaTypeMatch{kind: matchNone}
+			}
+			profile = true
+		}
+	}
+
+	return mediaTypeMatch{kind: kind, profile: profile}
+}
+
+func getPrecedenceWithOptions(match, previousMatch mediaTypeMatch, checkMediaType, previousMediaType MediaType) bool {
+	if match.kind != previousMatch.kind {
+		return match.kind > previousMatch.kind
+	}
+
+	if match.profile != previousMatch.profile {
+		return match.profile
+	}
+
+	return len(previousMediaType.Parameters()) < len(checkMediaType.Parameters())
+}
+
+// Choses a media type from available media types according to the Accept
+// header, the same way GetAcceptableMediaType does, but compares parameters
+// according to options instead of the default rule described on MatchOptions.
+func GetAcceptableMediaTypeWithOptions(request *http.Request, availableMediaTypes []MediaType, options MatchOptions) (MediaType, Parameters, error) {
+	// RFC 7231, 5.3.2. Accept
+	if len(availableMediaTypes) == 0 {
+		return MediaType{}, Parameters{}, ErrNoAvailableTypeGiven
+	}
+
+	acceptHeaders := request.Header.Values("Accept")
+	if len(acceptHeaders) == 0 {
+		return availableMediaTypes[0], Parameters{}, nil
+	}
+
+	s := acceptHeaders[0]
+
+	weights := make([]struct {
+		mediaType           MediaType
+		extensionParameters Parameters
+		weight              int
+		order               int
+		match               mediaTypeMatch
+	}, len(availableMediaTypes))
+
+	for mediaTypeCount := 0; len(s) > 0; mediaTypeCount++ {
+		if mediaTypeCount > 0 {
+			// every media type after the first one must start with a comma
+			if s[0] != ',' {
+				break
+			}
+			s = s[1:] // skip the comma
+		}
+
+		acceptableMediaType := MediaType{}
+		var consumed bool
+		acceptableMediaType.Type, acceptableMediaType.Subtype, s, consumed = consumeType(s)
+		if !consumed {
+			return MediaType{}, Parameters{}, ErrInvalidMediaType
+		}
+
+		acceptableMediaType.Suffix = getSuffix(acceptableMediaType.Subtype)
+		acceptableMediaParameters := make(Parameters)
+		weight := 1000 // 1.000
+
+		// media type parameters
+		for len(s) > 0 && s[0] == ';' {
+			s = s[1:] // skip the semicolon
+
+			var key, value string
+			key, value, s, consumed = consumeParameter(s)
+			if !consumed {
+				return MediaType{}, Parameters{}, ErrInvalidParameter
+			}
+
+			if key == "q" {
+				weight, consumed = getWeight(value)
+				if !consumed {
+					return MediaType{}, Parameters{}, ErrInvalidWeight
+				}
+				break // "q" parameter separates media type parameters from Accept extension parameters
+			}
+
+			acceptableMediaParameters[key] = value
+		}
+
+		acceptableMediaType.params = encodeParameters(acceptableMediaParameters)
+
+		extensionParameters := make(Parameters)
+		for len(s) > 0 && s[0] == ';' {
+			s = s[1:] // skip the semicolon
+
+			key, value, remaining, consumed := consumeParameter(s)
+			if !consumed {
+				return MediaType{}, Parameters{}, ErrInvalidParameter
+			}
+
+			s = remaining
+
+			extensionParameters[key] = value
+		}
+
+		for i := 0; i < len(availableMediaTypes); i++ {
+			match := compareMediaTypesWithOptions(acceptableMediaType, availableMediaTypes[i], options)
+			if match.kind != matchNone && getPrecedenceWithOptions(match, weights[i].match, acceptableMediaType, weights[i].mediaType) {
+				weights[i].mediaType = acceptableMediaType
+				weights[i].extensionParameters = extensionParameters
+				weights[i].weight = weight
+				weights[i].order = mediaTypeCount
+				weights[i].match = match
+			}
+		}
+
+		s = skipWhiteSpaces(s)
+	}
+
+	// there must not be anything left after parsing the header
+	if len(s) > 0 {
+		return MediaType{}, Parameters{}, ErrInvalidMediaRange
+	}
+
+	resultIndex := -1
+	for i := 0; i < len(availableMediaTypes); i++ {
+		if resultIndex != -1 {
+			if weights[i].weight > weights[resultIndex].weight ||
+				(weights[i].weight == weights[resultIndex].weight && weights[i].order < weights[resultIndex].order) {
+				resultIndex = i
+			}
+		} else if weights[i].weight > 0 {
+			resultIndex = i
+		}
+	}
+
+	if resultIndex == -1 {
+		return MediaType{}, Parameters{}, ErrNoAcceptableTypeFound
+	}
+
+	resultMediaType := availableMediaTypes[resultIndex]
+	if weights[resultIndex].match.kind == matchSuffix {
+		// carry the originally requested vendor type alongside the matched structured
+		// syntax suffix, so that callers can still pick a generic encoder for it
+		resultMediaType = NewMediaTypeWithParameters(
+			weights[resultIndex].mediaType.Type,
+			weights[resultIndex].mediaType.Subtype,
+			availableMediaTypes[resultIndex].Parameters(),
+		)
+	}
+
+	return resultMediaType, weights[resultIndex].extensionParameters, nil
+}
@@ -0,0 +1,102 @@
+package contenttype
+
+import (
+	"log"
+	"net/http"
+	"reflect"
+	"testing"
+)
+
+func TestGetAcceptableMediaTypeWithOptions(t *testing.T) {
+	testCases := []struct {
+		header              string
+		availableMediaTypes []MediaType
+		options             MatchOptions
+		result              MediaType
+	}{
+		{
+			"application/json;charset=utf-8",
+			[]MediaType{NewMediaTypeWithParameters("application", "json", Parameters{})},
+			MatchOptions{IgnoreParameters: []string{"charset"}},
+			NewMediaTypeWithParameters("application", "json", Parameters{}),
+		},
+		{
+			"application/json",
+			[]MediaType{NewMediaTypeWithParameters("application", "json", Parameters{"charset": "utf-8"})},
+			MatchOptions{},
+			NewMediaTypeWithParameters("application", "json", Parameters{"charset": "utf-8"}),
+		},
+		{
+			"application/json;charset=iso-8859-1",
+			[]MediaType{NewMediaTypeWithParameters("application", "json", Parameters{"charset": "utf-8"})},
+			MatchOptions{RequireAllAvailableParams: true, IgnoreParameters: []string{"charset"}},
+			NewMediaTypeWithParameters("application", "json", Parameters{"charset": "utf-8"}),
+		},
+		{
+			"application/ld+json;profile=\"https://a\",application/ld+json",
+			[]MediaType{NewMediaTypeWithParameters("application", "ld+json", Parameters{"profile": "https://a"})},
+			MatchOptions{ProfileParam: "profile"},
+			NewMediaTypeWithParameters("application", "ld+json", Parameters{"profile": "https://a"}),
+		},
+	}
+
+	for _, testCase := range testCases {
+		request, requestError := http.NewRequest(http.MethodGet, "http://test.test", nil)
+		if requestError != nil {
+			log.Fatal(requestError)
+		}
+
+		if len(testCase.header) > 0 {
+			request.Header.Set("Accept", testCase.header)
+		}
+
+		result, _, mediaTypeError := GetAcceptableMediaTypeWithOptions(request, testCase.availableMediaTypes, testCase.options)
+		if mediaTypeError != nil {
+			t.Errorf("Unexpected error \"%s\" for %s", mediaTypeError.Error(), testCase.header)
+		} else if result.Type != testCase.result.Type || result.Subtype != testCase.result.Subtype {
+			t.Errorf("Invalid content type, got %s/%s, exptected %s/%s for %s", result.Type, result.Subtype, testCase.result.Type, testCase.result.Subtype, testCase.header)
+		} else if !reflect.DeepEqual(result.Parameters(), testCase.result.Parameters()) {
+			t.Errorf("Wrong parameters, got %v, expected %v for %s", result.Parameters(), testCase.result.Parameters(), testCase.header)
+		}
+	}
+}
+
+func TestGetAcceptableMediaTypeWithOptionsErrors(t *testing.T) {
+	testCases := []struct {
+		header              string
+		availableMediaTypes []MediaType
+		options             MatchOptions
+		err                 error
+	}{
+		{
+			"application/json;charset=iso-8859-1",
+			[]MediaType{NewMediaTypeWithParameters("application", "json", Parameters{"charset": "utf-8"})},
+			MatchOptions{RequireAllAvailableParams: true},
+			ErrNoAcceptableTypeFound,
+		},
+		{
+			"application/ld+json;profile=\"https://b\"",
+			[]MediaType{NewMediaTypeWithParameters("application", "ld+json", Parameters{"profile": "https://a"})},
+			MatchOptions{ProfileParam: "profile"},
+			ErrNoAcceptableTypeFound,
+		},
+	}
+
+	for _, testCase := range testCases {
+		request, requestError := http.NewRequest(http.MethodGet, "http://test.test", nil)
+		if requestError != nil {
+			log.Fatal(requestError)
+		}
+
+		if len(testCase.header) > 0 {
+			request.Header.Set("Accept", testCase.header)
+		}
+
+		_, _, mediaTypeError := GetAcceptableMediaTypeWithOptions(request, testCase.availableMediaTypes, testCase.options)
+		if mediaTypeError == nil {
+			t.Errorf("Expected an error for %s", testCase.header)
+		} else if testCase.err != mediaTypeError {
+			t.Errorf("Unexpected error \"%s\", expected \"%s\" for %s", mediaTypeError.Error(), testCase.err.Error(), testCase.header)
+		}
+	}
+}
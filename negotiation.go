@@ -0,0 +1,105 @@
+package contenttype
+
+// A single entry of a weighted list header such as Accept-Encoding,
+// Accept-Language or Accept-Charset, i.e. a token together with its q-value.
+type weightedItem struct {
+	value  string
+	weight int
+}
+
+// Parses a comma separated list of tokens, each optionally followed by a
+// ";q=value" weight, as used by Accept-Encoding (RFC 9110, 12.5.3),
+// Accept-Language (RFC 9110, 12.5.4) and Accept-Charset (RFC 9110, 12.5.5).
+// Accept-Language ranges are tokenized the same way, since a BCP 47 language
+// tag is itself composed of token characters.
+func parseWeightedList(s string, invalidValueError error) ([]weightedItem, error) {
+	var items []weightedItem
+
+	for count := 0; len(s) > 0; count++ {
+		if count > 0 {
+			// every entry after the first one must start with a comma
+			if s[0] != ',' {
+				break
+			}
+			s = s[1:] // skip the comma
+		}
+
+		s = skipWhiteSpaces(s)
+
+		value, remaining, consumed := consumeToken(s)
+		if !consumed {
+			return nil, invalidValueError
+		}
+		s = remaining
+
+		item := weightedItem{value: value, weight: 1000} // 1.000
+
+		if len(s) > 0 && s[0] == ';' {
+			s = s[1:] // skip the semicolon
+
+			key, weightValue, remaining, consumed := consumeParameter(s)
+			if !consumed || key != "q" {
+				return nil, invalidValueError
+			}
+			s = remaining
+
+			item.weight, consumed = getWeight(weightValue)
+			if !consumed {
+				return nil, ErrInvalidWeight
+			}
+		}
+
+		items = append(items, item)
+
+		s = skipWhiteSpaces(s)
+	}
+
+	if len(s) > 0 {
+		return nil, invalidValueError
+	}
+
+	return items, nil
+}
+
+// Tracks the best header entry found so far for one available value.
+type weightedMatch struct {
+	weight    int
+	order     int
+	exact     bool
+	mentioned bool
+}
+
+// Records a candidate match for an available value, unless a more specific
+// (exact) match has already been recorded for it.
+func (match *weightedMatch) record(weight, order int, exact bool) {
+	if match.mentioned && !exact && match.exact {
+		return
+	}
+
+	match.weight = weight
+	match.order = order
+	match.exact = exact
+	match.mentioned = true
+}
+
+// Picks the index of the highest-weighted acceptable match, preferring an
+// exact match over a wildcard or prefix match of equal weight and then the
+// earliest-ordered one, or -1 if none of the available values are acceptable.
+func bestMatch(matches []weightedMatch) int {
+	resultIndex := -1
+
+	for i := range matches {
+		if !matches[i].mentioned || matches[i].weight == 0 {
+			continue
+		}
+
+		if resultIndex == -1 ||
+			matches[i].weight > matches[resultIndex].weight ||
+			(matches[i].weight == matches[resultIndex].weight && matches[i].exact && !matches[resultIndex].exact) ||
+			(matches[i].weight == matches[resultIndex].weight && matches[i].exact == matches[resultIndex].exact && matches[i].order < matches[resultIndex].order) {
+			resultIndex = i
+		}
+	}
+
+	return resultIndex
+}
@@ -0,0 +1,141 @@
+package contenttype
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// Produces the value to send in the response body for a negotiated request,
+// or an error. The request body, if any, has not been consumed and remains
+// available for Handler to read and decode itself.
+type Handler func(ctx context.Context, request *http.Request) (interface{}, error)
+
+// Writes value to writer, which has already been negotiated to mediaType with
+// the given Accept extension parameters. Registered with a Negotiator's
+// Encoders registry. writer is not the response itself, so that ServeHTTP can
+// buffer the output and still send the promised error response if Encoder
+// fails partway through.
+type Encoder func(writer io.Writer, value interface{}, mediaType MediaType, parameters Parameters) error
+
+// Writes the body of a response that Negotiator could not produce, e.g. a 406
+// Not Acceptable or a 415 Unsupported Media Type, listing the media types it
+// could have produced or accepted. Implementations must call
+// responseWriter.WriteHeader(status) themselves, which lets them negotiate
+// the error body's own Content-Type against the request's Accept header
+// before doing so.
+type ErrorEncoder func(responseWriter http.ResponseWriter, request *http.Request, status int, message string, availableMediaTypes []MediaType)
+
+// Writes a short plain text error message followed by one line per available
+// media type. Used by Negotiator when WriteError is nil.
+func defaultErrorEncoder(responseWriter http.ResponseWriter, request *http.Request, status int, message string, availableMediaTypes []MediaType) {
+	responseWriter.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	responseWriter.WriteHeader(status)
+
+	fmt.Fprintln(responseWriter, message)
+	for _, mediaType := range availableMediaTypes {
+		fmt.Fprintln(responseWriter, "-", mediaType.String())
+	}
+}
+
+// Negotiator is an http.Handler that wraps a Handler with Content-Type and
+// Accept negotiation, so that Handler only has to deal with a Go value: it
+// rejects a request whose Content-Type is not registered in Decoders with 415
+// Unsupported Media Type, negotiates the Accept header against Encoders and
+// responds with 406 Not Acceptable if no Encoder is acceptable, and only then
+// calls Handler and writes the returned value with the matched Encoder.
+type Negotiator struct {
+	Handler Handler
+
+	// Encoders associates a response media type with the Encoder used to
+	// write a value of that type. Must have at least one entry registered.
+	Encoders Registry
+
+	// Decoders associates an accepted request media type with a marker
+	// value; only its keys are consulted. A request's Content-Type is
+	// checked against Decoders only when Decoders has at least one entry, so
+	// a Negotiator whose Handler does not read a request body can leave it
+	// empty.
+	Decoders Registry
+
+	// DefaultType, when set, is used instead of GetAcceptableMediaType's
+	// default of the first registered Encoder when the request has no Accept
+	// header. If it is not a registered Encoders media type, such a request
+	// is answered with 500 Internal Server Error.
+	DefaultType MediaType
+
+	// WriteError formats the body of a 406, 415 or 500 response. If nil,
+	// defaultErrorEncoder is used.
+	WriteError ErrorEncoder
+}
+
+func (negotiator *Negotiator) writeError(responseWriter http.ResponseWriter, request *http.Request, status int, message string, availableMediaTypes []MediaType) {
+	writeError := negotiator.WriteError
+	if writeError == nil {
+		writeError = defaultErrorEncoder
+	}
+
+	writeError(responseWriter, request, status, message, availableMediaTypes)
+}
+
+func (negotiator *Negotiator) ServeHTTP(responseWriter http.ResponseWriter, request *http.Request) {
+	if len(negotiator.Decoders.types) > 0 {
+		contentType, contentTypeError := GetMediaType(request)
+		if contentTypeError != nil {
+			negotiator.writeError(responseWriter, request, http.StatusUnsupportedMediaType, contentTypeError.Error(), negotiator.Decoders.types)
+			return
+		}
+
+		if contentType != (MediaType{}) && !negotiator.Decoders.Accepts(contentType) {
+			negotiator.writeError(responseWriter, request, http.StatusUnsupportedMediaType, fmt.Sprintf("Unsupported media type %q", contentType.String()), negotiator.Decoders.types)
+			return
+		}
+	}
+
+	var mediaType MediaType
+	var encoderValue interface{}
+	var parameters Parameters
+
+	if _, found := request.Header["Accept"]; !found && negotiator.DefaultType != (MediaType{}) {
+		value, registered := negotiator.Encoders.values[negotiator.DefaultType]
+		if !registered {
+			negotiator.writeError(responseWriter, request, http.StatusInternalServerError, "Default media type is not registered", negotiator.Encoders.types)
+			return
+		}
+
+		mediaType, encoderValue, parameters = negotiator.DefaultType, value, Parameters{}
+	} else {
+		var negotiateError error
+		mediaType, encoderValue, parameters, negotiateError = negotiator.Encoders.Negotiate(request)
+		if negotiateError != nil {
+			negotiator.writeError(responseWriter, request, http.StatusNotAcceptable, negotiateError.Error(), negotiator.Encoders.types)
+			return
+		}
+	}
+
+	encoder, ok := encoderValue.(Encoder)
+	if !ok {
+		negotiator.writeError(responseWriter, request, http.StatusInternalServerError, "Encoder is not registered correctly", negotiator.Encoders.types)
+		return
+	}
+
+	value, handlerError := negotiator.Handler(request.Context(), request)
+	if handlerError != nil {
+		negotiator.writeError(responseWriter, request, http.StatusInternalServerError, handlerError.Error(), nil)
+		return
+	}
+
+	// Encode into a buffer rather than responseWriter directly, so that an
+	// encoder failing partway through does not leave a truncated 200 response
+	// committed with no way to still send the promised error status.
+	var body bytes.Buffer
+	if encodeError := encoder(&body, value, mediaType, parameters); encodeError != nil {
+		negotiator.writeError(responseWriter, request, http.StatusInternalServerError, encodeError.Error(), nil)
+		return
+	}
+
+	responseWriter.Header().Set("Content-Type", mediaType.String())
+	responseWriter.Write(body.Bytes())
+}
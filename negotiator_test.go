@@ -0,0 +1,145 @@
+package contenttype
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func jsonEncoder(writer io.Writer, value interface{}, mediaType MediaType, parameters Parameters) error {
+	_, err := fmt.Fprintf(writer, "%v", value)
+	return err
+}
+
+func newTestNegotiator() *Negotiator {
+	negotiator := &Negotiator{
+		Handler: func(ctx context.Context, request *http.Request) (interface{}, error) {
+			return "hello", nil
+		},
+	}
+	negotiator.Encoders.Register(NewMediaType("application/json"), Encoder(jsonEncoder))
+	negotiator.Encoders.Register(NewMediaType("text/plain"), Encoder(jsonEncoder))
+
+	return negotiator
+}
+
+func TestNegotiatorServeHTTP(t *testing.T) {
+	testCases := []struct {
+		accept      string
+		contentType string
+	}{
+		{"application/json", "application/json"},
+		{"text/plain", "text/plain"},
+		{"", "application/json"},
+	}
+
+	for _, testCase := range testCases {
+		negotiator := newTestNegotiator()
+
+		request := httptest.NewRequest(http.MethodGet, "http://test.test", nil)
+		if len(testCase.accept) > 0 {
+			request.Header.Set("Accept", testCase.accept)
+		}
+
+		responseRecorder := httptest.NewRecorder()
+		negotiator.ServeHTTP(responseRecorder, request)
+
+		if responseRecorder.Code != http.StatusOK {
+			t.Errorf("Unexpected status %d for Accept %q", responseRecorder.Code, testCase.accept)
+		} else if contentType := responseRecorder.Header().Get("Content-Type"); contentType != testCase.contentType {
+			t.Errorf("Wrong Content-Type, got %s, expected %s for Accept %q", contentType, testCase.contentType, testCase.accept)
+		} else if body := responseRecorder.Body.String(); body != "hello" {
+			t.Errorf("Wrong body, got %s, expected hello for Accept %q", body, testCase.accept)
+		}
+	}
+}
+
+func TestNegotiatorServeHTTPSuffixMatch(t *testing.T) {
+	negotiator := newTestNegotiator()
+
+	request := httptest.NewRequest(http.MethodGet, "http://test.test", nil)
+	request.Header.Set("Accept", "application/vnd.docker.distribution.manifest.v2+json")
+
+	responseRecorder := httptest.NewRecorder()
+	negotiator.ServeHTTP(responseRecorder, request)
+
+	wantContentType := "application/vnd.docker.distribution.manifest.v2+json"
+	if responseRecorder.Code != http.StatusOK {
+		t.Errorf("Unexpected status %d", responseRecorder.Code)
+	} else if contentType := responseRecorder.Header().Get("Content-Type"); contentType != wantContentType {
+		t.Errorf("Wrong Content-Type, got %s, expected %s", contentType, wantContentType)
+	} else if body := responseRecorder.Body.String(); body != "hello" {
+		t.Errorf("Wrong body, got %s, expected hello", body)
+	}
+}
+
+func TestNegotiatorServeHTTPEncoderFailureDoesNotLeakPartialOutput(t *testing.T) {
+	negotiator := newTestNegotiator()
+	negotiator.Encoders.Register(NewMediaType("application/json"), Encoder(
+		func(writer io.Writer, value interface{}, mediaType MediaType, parameters Parameters) error {
+			io.WriteString(writer, "PARTIAL-OUTPUT-BEFORE-FAILURE")
+			return fmt.Errorf("boom")
+		},
+	))
+
+	request := httptest.NewRequest(http.MethodGet, "http://test.test", nil)
+	request.Header.Set("Accept", "application/json")
+
+	responseRecorder := httptest.NewRecorder()
+	negotiator.ServeHTTP(responseRecorder, request)
+
+	if responseRecorder.Code != http.StatusInternalServerError {
+		t.Errorf("Unexpected status %d, expected %d", responseRecorder.Code, http.StatusInternalServerError)
+	} else if body := responseRecorder.Body.String(); strings.Contains(body, "PARTIAL-OUTPUT-BEFORE-FAILURE") {
+		t.Errorf("Expected no partial encoder output in the error response, got %s", body)
+	}
+}
+
+func TestNegotiatorNotAcceptable(t *testing.T) {
+	negotiator := newTestNegotiator()
+
+	request := httptest.NewRequest(http.MethodGet, "http://test.test", nil)
+	request.Header.Set("Accept", "application/xml")
+
+	responseRecorder := httptest.NewRecorder()
+	negotiator.ServeHTTP(responseRecorder, request)
+
+	if responseRecorder.Code != http.StatusNotAcceptable {
+		t.Errorf("Unexpected status %d, expected %d", responseRecorder.Code, http.StatusNotAcceptable)
+	} else if body := responseRecorder.Body.String(); !strings.Contains(body, "application/json") {
+		t.Errorf("Expected error body to list available media types, got %s", body)
+	}
+}
+
+func TestNegotiatorUnsupportedMediaType(t *testing.T) {
+	negotiator := newTestNegotiator()
+	negotiator.Decoders.Register(NewMediaType("application/json"), struct{}{})
+
+	request := httptest.NewRequest(http.MethodPost, "http://test.test", nil)
+	request.Header.Set("Content-Type", "application/xml")
+
+	responseRecorder := httptest.NewRecorder()
+	negotiator.ServeHTTP(responseRecorder, request)
+
+	if responseRecorder.Code != http.StatusUnsupportedMediaType {
+		t.Errorf("Unexpected status %d, expected %d", responseRecorder.Code, http.StatusUnsupportedMediaType)
+	}
+}
+
+func TestNegotiatorDefaultType(t *testing.T) {
+	negotiator := newTestNegotiator()
+	negotiator.DefaultType = NewMediaType("text/plain")
+
+	request := httptest.NewRequest(http.MethodGet, "http://test.test", nil)
+
+	responseRecorder := httptest.NewRecorder()
+	negotiator.ServeHTTP(responseRecorder, request)
+
+	if contentType := responseRecorder.Header().Get("Content-Type"); contentType != "text/plain" {
+		t.Errorf("Wrong Content-Type, got %s, expected text/plain", contentType)
+	}
+}
@@ -0,0 +1,74 @@
+package contenttype
+
+import (
+	"errors"
+	"net/http"
+)
+
+// No value is registered in the registry for the media type.
+var ErrMediaTypeNotRegistered = errors.New("Media type not registered")
+
+// A Registry associates media types with arbitrary values, e.g. encoders or
+// decoders, and uses GetAcceptableMediaType to pick one of the registered
+// media types according to the Accept header of a request.
+type Registry struct {
+	values map[MediaType]interface{}
+	types  []MediaType
+}
+
+// Associates a value with a media type. Registering the same media type
+// again replaces the previously registered value.
+func (registry *Registry) Register(mediaType MediaType, value interface{}) {
+	if registry.values == nil {
+		registry.values = make(map[MediaType]interface{})
+	}
+
+	if _, exists := registry.values[mediaType]; !exists {
+		registry.types = append(registry.types, mediaType)
+	}
+
+	registry.values[mediaType] = value
+}
+
+// Resolves mediaType to a registered entry, following GetAcceptableMediaType's
+// structured syntax suffix (RFC 6839) convention of carrying the originally
+// requested vendor type alongside the matched generic type's parameters.
+func (registry *Registry) lookup(mediaType MediaType) (MediaType, interface{}, bool) {
+	if value, found := registry.values[mediaType]; found {
+		return mediaType, value, true
+	}
+
+	for _, registeredMediaType := range registry.types {
+		if registeredMediaType.Type == mediaType.Type && registeredMediaType.Subtype == mediaType.Suffix {
+			return mediaType, registry.values[registeredMediaType], true
+		}
+	}
+
+	return MediaType{}, nil, false
+}
+
+// Reports whether mediaType, e.g. one returned by GetMediaType, has been
+// registered, or, for a media type with a structured syntax suffix, whether a
+// media type of the same type whose subtype equals that suffix has been.
+func (registry *Registry) Accepts(mediaType MediaType) bool {
+	_, _, found := registry.lookup(mediaType)
+	return found
+}
+
+// Picks the most acceptable registered media type for the request's Accept
+// header and returns it along with its registered value and the Accept
+// extension parameters. Returns ErrNoAvailableTypeGiven if no media type has
+// been registered, and the errors of GetAcceptableMediaType otherwise.
+func (registry *Registry) Negotiate(request *http.Request) (MediaType, interface{}, Parameters, error) {
+	mediaType, extensionParameters, err := GetAcceptableMediaType(request, registry.types)
+	if err != nil {
+		return MediaType{}, nil, Parameters{}, err
+	}
+
+	resolvedMediaType, value, found := registry.lookup(mediaType)
+	if !found {
+		return MediaType{}, nil, Parameters{}, ErrMediaTypeNotRegistered
+	}
+
+	return resolvedMediaType, value, extensionParameters, nil
+}
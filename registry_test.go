@@ -0,0 +1,110 @@
+package contenttype
+
+import (
+	"log"
+	"net/http"
+	"testing"
+)
+
+func TestRegistryNegotiate(t *testing.T) {
+	testCases := []struct {
+		header      string
+		registered  map[MediaType]interface{}
+		result      MediaType
+		resultValue interface{}
+	}{
+		{
+			"application/json",
+			map[MediaType]interface{}{
+				NewMediaType("application/json"): "json",
+				NewMediaType("application/xml"):  "xml",
+			},
+			NewMediaType("application/json"), "json",
+		},
+		{
+			"application/xml;q=0.5,application/json",
+			map[MediaType]interface{}{
+				NewMediaType("application/json"): "json",
+				NewMediaType("application/xml"):  "xml",
+			},
+			NewMediaType("application/json"), "json",
+		},
+		{
+			"application/vnd.api+json",
+			map[MediaType]interface{}{
+				NewMediaType("application/json"): "json",
+			},
+			NewMediaType("application/vnd.api+json"), "json",
+		},
+		{
+			"",
+			map[MediaType]interface{}{
+				NewMediaType("application/json"): "json",
+			},
+			NewMediaType("application/json"), "json",
+		},
+	}
+
+	for _, testCase := range testCases {
+		var registry Registry
+		for mediaType, value := range testCase.registered {
+			registry.Register(mediaType, value)
+		}
+
+		request, requestError := http.NewRequest(http.MethodGet, "http://test.test", nil)
+		if requestError != nil {
+			log.Fatal(requestError)
+		}
+
+		if len(testCase.header) > 0 {
+			request.Header.Set("Accept", testCase.header)
+		}
+
+		result, value, _, negotiateError := registry.Negotiate(request)
+		if negotiateError != nil {
+			t.Errorf("Unexpected error \"%s\" for %s", negotiateError.Error(), testCase.header)
+		} else if result != testCase.result {
+			t.Errorf("Invalid media type, got %s, expected %s for %s", result.String(), testCase.result.String(), testCase.header)
+		} else if value != testCase.resultValue {
+			t.Errorf("Invalid value, got %v, expected %v for %s", value, testCase.resultValue, testCase.header)
+		}
+	}
+}
+
+func TestRegistryNegotiateErrors(t *testing.T) {
+	testCases := []struct {
+		header     string
+		registered map[MediaType]interface{}
+		err        error
+	}{
+		{"", map[MediaType]interface{}{}, ErrNoAvailableTypeGiven},
+		{
+			"application/xml",
+			map[MediaType]interface{}{NewMediaType("application/json"): "json"},
+			ErrNoAcceptableTypeFound,
+		},
+	}
+
+	for _, testCase := range testCases {
+		var registry Registry
+		for mediaType, value := range testCase.registered {
+			registry.Register(mediaType, value)
+		}
+
+		request, requestError := http.NewRequest(http.MethodGet, "http://test.test", nil)
+		if requestError != nil {
+			log.Fatal(requestError)
+		}
+
+		if len(testCase.header) > 0 {
+			request.Header.Set("Accept", testCase.header)
+		}
+
+		_, _, _, negotiateError := registry.Negotiate(request)
+		if negotiateError == nil {
+			t.Errorf("Expected an error for %s", testCase.header)
+		} else if testCase.err != negotiateError {
+			t.Errorf("Unexpected error \"%s\", expected \"%s\" for %s", negotiateError.Error(), testCase.err.Error(), testCase.header)
+		}
+	}
+}